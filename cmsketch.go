@@ -0,0 +1,109 @@
+package tcache
+
+// cmSketch is a count-min sketch: width 4-bit counters per row, one row per
+// hash function, used to estimate how often a key has been seen without
+// keeping any per-key state. Counters are packed two-per-byte and clamp at
+// 15; once the total number of increments reaches sampleSize every counter
+// is halved, so the sketch tracks recent activity rather than accumulating
+// forever.
+type cmSketch struct {
+	rows       [4][]uint8
+	width      uint64
+	seeds      [4]uint64
+	count      int64
+	sampleSize int64
+}
+
+// defaultCMSeeds are fixed, arbitrary odd constants used to derive 4
+// independent-enough hash values from a single 64-bit key hash via mix.
+var defaultCMSeeds = [4]uint64{
+	0x9e3779b97f4a7c15,
+	0xbf58476d1ce4e5b9,
+	0x94d049bb133111eb,
+	0xd6e8feb86659fd93,
+}
+
+func newCMSketch(width uint64, sampleSize int64) *cmSketch {
+	if width < 16 {
+		width = 16
+	}
+	s := &cmSketch{width: width, seeds: defaultCMSeeds, sampleSize: sampleSize}
+	for i := range s.rows {
+		s.rows[i] = make([]uint8, (width+1)/2)
+	}
+	return s
+}
+
+func mix(h, seed uint64) uint64 {
+	x := h ^ seed
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+	return x
+}
+
+func (s *cmSketch) indices(h uint64) [4]uint64 {
+	var idx [4]uint64
+	for i := 0; i < 4; i++ {
+		idx[i] = mix(h, s.seeds[i]) % s.width
+	}
+	return idx
+}
+
+func nibbleGet(row []uint8, idx uint64) uint8 {
+	b := row[idx/2]
+	if idx%2 == 0 {
+		return b & 0x0F
+	}
+	return b >> 4
+}
+
+func nibbleSet(row []uint8, idx uint64, v uint8) {
+	i := idx / 2
+	if idx%2 == 0 {
+		row[i] = (row[i] & 0xF0) | v
+	} else {
+		row[i] = (row[i] & 0x0F) | (v << 4)
+	}
+}
+
+// add increments every counter h maps to, aging the whole sketch first if
+// enough increments have accumulated since the last aging pass.
+func (s *cmSketch) add(h uint64) {
+	for i, idx := range s.indices(h) {
+		if v := nibbleGet(s.rows[i], idx); v < 15 {
+			nibbleSet(s.rows[i], idx, v+1)
+		}
+	}
+	s.count++
+	if s.count >= s.sampleSize {
+		s.age()
+	}
+}
+
+// age halves every counter, keeping frequency estimates biased toward
+// recent activity instead of growing unbounded.
+func (s *cmSketch) age() {
+	for _, row := range s.rows {
+		for i, b := range row {
+			low := b & 0x0F
+			high := b >> 4
+			row[i] = (low >> 1) | ((high >> 1) << 4)
+		}
+	}
+	s.count = 0
+}
+
+// estimate returns h's estimated frequency: the minimum counter across all
+// rows, which count-min sketches use to cancel out hash collisions.
+func (s *cmSketch) estimate(h uint64) uint8 {
+	min := uint8(15)
+	for i, idx := range s.indices(h) {
+		if v := nibbleGet(s.rows[i], idx); v < min {
+			min = v
+		}
+	}
+	return min
+}
@@ -0,0 +1,38 @@
+package tcache
+
+// WithCost sets the function used to compute an entry's admission cost.
+// When combined with WithMaxCost, Set/SetWithTTL evict entries until
+// totalCost+newCost fits within the budget, instead of only counting
+// items. This is what makes byte-budgeted caching (e.g. for HTTP response
+// bodies or other blobs) meaningful. Eviction stops when the policy's
+// victim is the key being set, so an entry that costs more than the budget
+// on its own is still stored and the total can exceed it.
+func WithCost[K comparable, V any](cost func(K, V) int64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.costFn = cost
+	}
+}
+
+// WithWeigher is WithCost for callers who only care about the value, not
+// the key; a common shape for struct payloads.
+func WithWeigher[K comparable, V any](weigh func(V) int64) Option[K, V] {
+	return WithCost[K, V](func(_ K, v V) int64 {
+		return weigh(v)
+	})
+}
+
+// WithMaxCost caps the total cost the cache will hold, as computed by the
+// function passed to WithCost/WithWeigher. Without a cost function every
+// entry costs 0, so WithMaxCost alone has no effect.
+func WithMaxCost[K comparable, V any](maxCost int64) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.maxCost = maxCost
+	}
+}
+
+// ByteCost is a ready-made Cost function for caches whose values are raw
+// byte slices, such as cached HTTP response bodies: an entry costs len(v)
+// bytes. Use it with WithCost[K, []byte](ByteCost[K]).
+func ByteCost[K comparable](_ K, v []byte) int64 {
+	return int64(len(v))
+}
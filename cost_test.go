@@ -0,0 +1,27 @@
+package tcache
+
+import "testing"
+
+// TestSetWithCostUpdateDoesNotEvictSelf covers a regression where updating
+// the cache's only entry to a larger cost caused the maxCost eviction loop
+// to pick that same entry as its own eviction victim, deleting it from
+// c.items while the policy still tracked it as present. The next eviction
+// then dereferenced a nil *Item and panicked.
+func TestSetWithCostUpdateDoesNotEvictSelf(t *testing.T) {
+	c := New[string, []byte](10, WithCost[string, []byte](ByteCost[string]), WithMaxCost[string, []byte](10))
+	defer c.Close()
+
+	c.Set("a", make([]byte, 5))
+	c.Set("a", make([]byte, 16)) // bigger than maxCost on its own
+
+	if !c.Has("a") {
+		t.Fatalf(`expected "a" to still be present after an over-budget update`)
+	}
+
+	// This must not panic on a phantom policy entry left behind by the bug.
+	c.Set("b", make([]byte, 1))
+
+	if !c.Has("b") {
+		t.Fatalf(`expected "b" to be present after insertion`)
+	}
+}
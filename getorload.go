@@ -0,0 +1,72 @@
+package tcache
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// call represents a loader invocation for a single key that is in flight
+// on behalf of one or more concurrent GetOrLoad callers.
+type call[V any] struct {
+	wg    sync.WaitGroup
+	value V
+	err   error
+}
+
+// GetOrLoad returns the cached value for key if present. Otherwise it calls
+// loader exactly once, no matter how many goroutines call GetOrLoad for the
+// same key concurrently: the first caller runs loader and the rest block on
+// its result. On success the result is cached with the TTL loader returned
+// (via SetWithTTL); a zero TTL means no expiration. This is the standard
+// fix for thundering-herd loads against a backing store on a cache miss.
+func (c *Cache[K, V]) GetOrLoad(key K, loader func(K) (V, time.Duration, error)) (V, error) {
+	if value, ok := c.Get(key); ok {
+		return value, nil
+	}
+
+	c.mu.Lock()
+	if c.inflight == nil {
+		c.inflight = make(map[K]*call[V])
+	}
+	if cl, ok := c.inflight[key]; ok {
+		c.mu.Unlock()
+		cl.wg.Wait()
+		return cl.value, cl.err
+	}
+
+	cl := &call[V]{}
+	cl.wg.Add(1)
+	c.inflight[key] = cl
+	c.mu.Unlock()
+
+	value, ttl, err := c.runLoader(key, loader, cl)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+
+	c.SetWithTTL(key, value, ttl)
+	return value, nil
+}
+
+// runLoader calls loader on behalf of cl, guaranteeing that the in-flight
+// entry for key is always removed and every waiter on cl.wg is always
+// released, even if loader panics — otherwise every caller for key would
+// block on cl.wg.Wait() forever.
+func (c *Cache[K, V]) runLoader(key K, loader func(K) (V, time.Duration, error), cl *call[V]) (value V, ttl time.Duration, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var zero V
+			value, ttl, err = zero, 0, fmt.Errorf("tcache: loader panicked: %v", r)
+		}
+		cl.value, cl.err = value, err
+
+		c.mu.Lock()
+		delete(c.inflight, key)
+		c.mu.Unlock()
+		cl.wg.Done()
+	}()
+
+	return loader(key)
+}
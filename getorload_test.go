@@ -0,0 +1,96 @@
+package tcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestGetOrLoadCoalescesConcurrentCallers verifies N concurrent GetOrLoad
+// calls for the same missing key invoke loader exactly once, with every
+// caller getting the one load's result — this is the feature's whole
+// purpose, not just its panic-safety edge case.
+func TestGetOrLoadCoalescesConcurrentCallers(t *testing.T) {
+	c := New[string, int](10)
+	defer c.Close()
+
+	const callers = 20
+	var calls int32
+	release := make(chan struct{})
+	loader := func(key string) (int, time.Duration, error) {
+		atomic.AddInt32(&calls, 1)
+		<-release // held open so every caller below joins the same in-flight call
+		return 7, 0, nil
+	}
+
+	var wg sync.WaitGroup
+	values := make([]int, callers)
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			v, err := c.GetOrLoad("k", loader)
+			values[i] = v
+			errs[i] = err
+		}(i)
+	}
+
+	time.Sleep(20 * time.Millisecond) // let every goroutine enqueue onto the in-flight call
+	close(release)
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("loader invoked %d times, want 1", got)
+	}
+	for i := 0; i < callers; i++ {
+		if errs[i] != nil || values[i] != 7 {
+			t.Errorf("caller %d: GetOrLoad = (%d, %v), want (7, nil)", i, values[i], errs[i])
+		}
+	}
+}
+
+// TestGetOrLoadPanicReleasesWaiters verifies a panicking loader turns into
+// an error for every concurrent caller instead of wedging the key forever.
+func TestGetOrLoadPanicReleasesWaiters(t *testing.T) {
+	c := New[string, int](10)
+	defer c.Close()
+
+	const callers = 5
+	release := make(chan struct{})
+	panicLoader := func(key string) (int, time.Duration, error) {
+		<-release // held open so every caller below joins the same in-flight call
+		panic("boom")
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := c.GetOrLoad("k", panicLoader)
+			errs[i] = err
+		}(i)
+	}
+
+	time.Sleep(20 * time.Millisecond) // let every goroutine enqueue onto the in-flight call
+	close(release)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("caller %d: expected an error from the panicking loader, got nil", i)
+		}
+	}
+
+	// The in-flight entry must have been cleaned up: a later, successful
+	// load for the same key should not be stuck behind the panic.
+	value, err := c.GetOrLoad("k", func(key string) (int, time.Duration, error) {
+		return 42, 0, nil
+	})
+	if err != nil || value != 42 {
+		t.Fatalf("GetOrLoad after panic = (%d, %v), want (42, nil)", value, err)
+	}
+}
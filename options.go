@@ -0,0 +1,12 @@
+package tcache
+
+// Option configures a Cache at construction time. See New.
+type Option[K comparable, V any] func(*Cache[K, V])
+
+// WithPolicy selects the eviction policy a Cache uses once it is full.
+// The default, used when no Option is given, is NewLRUPolicy.
+func WithPolicy[K comparable, V any](p Policy[K, V]) Option[K, V] {
+	return func(c *Cache[K, V]) {
+		c.policy = p
+	}
+}
@@ -0,0 +1,309 @@
+package tcache
+
+import (
+	"container/list"
+	"sort"
+)
+
+// Policy decides which key to evict next and how access/insertion/removal
+// affect that decision. Cache serializes all calls into a Policy under its
+// own lock, so implementations do not need to be safe for concurrent use.
+type Policy[K comparable, V any] interface {
+	// OnAccess is called whenever an existing key is read by Get.
+	OnAccess(key K)
+	// OnInsert is called whenever a key is added to the cache, including
+	// when an existing key is overwritten by Set/SetWithTTL.
+	OnInsert(key K)
+	// OnRemove is called whenever a key leaves the cache, whether by
+	// Delete, expiration or eviction.
+	OnRemove(key K)
+	// Evict returns the key the policy considers the best eviction
+	// candidate. ok is false if the policy is tracking no keys.
+	Evict() (key K, ok bool)
+	// Snapshot returns every tracked key ordered best-to-worst (the key
+	// Evict would return last comes first, the key it would return right
+	// now comes last), each paired with an opaque Weight the policy
+	// defines for itself: lfuPolicy uses it for a key's access frequency;
+	// lruPolicy and fifoPolicy ignore it since slice order alone conveys
+	// their state. Used by Cache.Save to persist eviction order and
+	// frequency, not just values.
+	Snapshot() []PolicyEntry[K]
+	// Restore replaces the policy's internal state with entries previously
+	// returned by Snapshot, in the same order. Used by Cache.Load.
+	Restore(entries []PolicyEntry[K])
+}
+
+// PolicyEntry is one key tracked by a Policy, as returned by Snapshot and
+// consumed by Restore.
+type PolicyEntry[K comparable] struct {
+	Key    K
+	Weight int64
+}
+
+// lruPolicy evicts the least-recently-used key, using the same
+// container/list-based ordering tcache has always used.
+type lruPolicy[K comparable, V any] struct {
+	order *list.List
+	elems map[K]*list.Element
+}
+
+// NewLRUPolicy returns a Policy that evicts the least-recently-used key.
+// It is the default policy used by New.
+func NewLRUPolicy[K comparable, V any]() Policy[K, V] {
+	return &lruPolicy[K, V]{order: list.New(), elems: make(map[K]*list.Element)}
+}
+
+func (p *lruPolicy[K, V]) OnAccess(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.MoveToFront(e)
+	}
+}
+
+func (p *lruPolicy[K, V]) OnInsert(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.MoveToFront(e)
+		return
+	}
+	p.elems[key] = p.order.PushFront(key)
+}
+
+func (p *lruPolicy[K, V]) OnRemove(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.Remove(e)
+		delete(p.elems, key)
+	}
+}
+
+func (p *lruPolicy[K, V]) Evict() (K, bool) {
+	back := p.order.Back()
+	if back == nil {
+		var zero K
+		return zero, false
+	}
+	return back.Value.(K), true
+}
+
+// Snapshot returns keys from most- to least-recently-used. Weight is
+// always 0; LRU's state is entirely conveyed by order.
+func (p *lruPolicy[K, V]) Snapshot() []PolicyEntry[K] {
+	entries := make([]PolicyEntry[K], 0, p.order.Len())
+	for e := p.order.Front(); e != nil; e = e.Next() {
+		entries = append(entries, PolicyEntry[K]{Key: e.Value.(K)})
+	}
+	return entries
+}
+
+func (p *lruPolicy[K, V]) Restore(entries []PolicyEntry[K]) {
+	p.order = list.New()
+	p.elems = make(map[K]*list.Element, len(entries))
+	for _, e := range entries {
+		p.elems[e.Key] = p.order.PushBack(e.Key)
+	}
+}
+
+// fifoPolicy evicts keys in the order they were first inserted; unlike LRU
+// it does not reorder on access.
+type fifoPolicy[K comparable, V any] struct {
+	order *list.List
+	elems map[K]*list.Element
+}
+
+// NewFIFOPolicy returns a Policy that evicts the oldest-inserted key,
+// ignoring subsequent accesses.
+func NewFIFOPolicy[K comparable, V any]() Policy[K, V] {
+	return &fifoPolicy[K, V]{order: list.New(), elems: make(map[K]*list.Element)}
+}
+
+func (p *fifoPolicy[K, V]) OnAccess(key K) {}
+
+func (p *fifoPolicy[K, V]) OnInsert(key K) {
+	if _, ok := p.elems[key]; ok {
+		return
+	}
+	p.elems[key] = p.order.PushFront(key)
+}
+
+func (p *fifoPolicy[K, V]) OnRemove(key K) {
+	if e, ok := p.elems[key]; ok {
+		p.order.Remove(e)
+		delete(p.elems, key)
+	}
+}
+
+func (p *fifoPolicy[K, V]) Evict() (K, bool) {
+	back := p.order.Back()
+	if back == nil {
+		var zero K
+		return zero, false
+	}
+	return back.Value.(K), true
+}
+
+// Snapshot returns keys from newest- to oldest-inserted. Weight is always
+// 0; FIFO's state is entirely conveyed by order.
+func (p *fifoPolicy[K, V]) Snapshot() []PolicyEntry[K] {
+	entries := make([]PolicyEntry[K], 0, p.order.Len())
+	for e := p.order.Front(); e != nil; e = e.Next() {
+		entries = append(entries, PolicyEntry[K]{Key: e.Value.(K)})
+	}
+	return entries
+}
+
+func (p *fifoPolicy[K, V]) Restore(entries []PolicyEntry[K]) {
+	p.order = list.New()
+	p.elems = make(map[K]*list.Element, len(entries))
+	for _, e := range entries {
+		p.elems[e.Key] = p.order.PushBack(e.Key)
+	}
+}
+
+// lfuPolicy evicts the least-frequently-used key. It tracks, for every
+// frequency count seen, a list.List of the keys currently at that count, so
+// that both "bump a key's frequency" and "evict the least-frequent key" are
+// O(1): bumping moves a key from the list for freq to the list for freq+1,
+// and eviction pops from the list for minFreq.
+type lfuPolicy[K comparable, V any] struct {
+	freqOf  map[K]int
+	nodes   map[K]*list.Element
+	buckets map[int]*list.List
+	minFreq int
+}
+
+// NewLFUPolicy returns a Policy that evicts the least-frequently-used key,
+// which tends to beat LRU on workloads with a small set of hot keys mixed
+// with a long tail of one-off lookups.
+func NewLFUPolicy[K comparable, V any]() Policy[K, V] {
+	return &lfuPolicy[K, V]{
+		freqOf:  make(map[K]int),
+		nodes:   make(map[K]*list.Element),
+		buckets: make(map[int]*list.List),
+	}
+}
+
+// bump moves key from its current frequency bucket to the next one,
+// creating the destination bucket if needed and dropping the source bucket
+// once it is empty.
+func (p *lfuPolicy[K, V]) bump(key K, freq int) {
+	if l := p.buckets[freq]; l != nil {
+		l.Remove(p.nodes[key])
+		if l.Len() == 0 {
+			delete(p.buckets, freq)
+			if p.minFreq == freq {
+				p.minFreq = freq + 1
+			}
+		}
+	}
+	newFreq := freq + 1
+	p.freqOf[key] = newFreq
+	l := p.buckets[newFreq]
+	if l == nil {
+		l = list.New()
+		p.buckets[newFreq] = l
+	}
+	p.nodes[key] = l.PushFront(key)
+}
+
+func (p *lfuPolicy[K, V]) OnAccess(key K) {
+	if freq, ok := p.freqOf[key]; ok {
+		p.bump(key, freq)
+	}
+}
+
+func (p *lfuPolicy[K, V]) OnInsert(key K) {
+	if freq, ok := p.freqOf[key]; ok {
+		p.bump(key, freq)
+		return
+	}
+	l := p.buckets[1]
+	if l == nil {
+		l = list.New()
+		p.buckets[1] = l
+	}
+	p.freqOf[key] = 1
+	p.nodes[key] = l.PushFront(key)
+	p.minFreq = 1
+}
+
+func (p *lfuPolicy[K, V]) OnRemove(key K) {
+	freq, ok := p.freqOf[key]
+	if !ok {
+		return
+	}
+	if l := p.buckets[freq]; l != nil {
+		l.Remove(p.nodes[key])
+		if l.Len() == 0 {
+			delete(p.buckets, freq)
+		}
+	}
+	delete(p.freqOf, key)
+	delete(p.nodes, key)
+}
+
+func (p *lfuPolicy[K, V]) Evict() (K, bool) {
+	l := p.buckets[p.minFreq]
+	if l == nil || l.Len() == 0 {
+		// minFreq goes stale whenever OnRemove (on Delete, expiration or
+		// eviction) takes out the last key at that frequency; fall back to
+		// a scan to find the new minimum before giving up.
+		found := false
+		for freq, candidate := range p.buckets {
+			if candidate.Len() == 0 {
+				continue
+			}
+			if !found || freq < p.minFreq {
+				p.minFreq = freq
+				l = candidate
+				found = true
+			}
+		}
+		if !found {
+			var zero K
+			return zero, false
+		}
+	}
+	back := l.Back()
+	return back.Value.(K), true
+}
+
+// Snapshot returns keys ordered from most- to least-frequently-used, each
+// paired with its access frequency as Weight, so Restore can bring a key
+// back at the frequency it had rather than resetting it to 1.
+func (p *lfuPolicy[K, V]) Snapshot() []PolicyEntry[K] {
+	freqs := make([]int, 0, len(p.buckets))
+	for freq := range p.buckets {
+		freqs = append(freqs, freq)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(freqs)))
+
+	entries := make([]PolicyEntry[K], 0, len(p.freqOf))
+	for _, freq := range freqs {
+		for e := p.buckets[freq].Front(); e != nil; e = e.Next() {
+			entries = append(entries, PolicyEntry[K]{Key: e.Value.(K), Weight: int64(freq)})
+		}
+	}
+	return entries
+}
+
+func (p *lfuPolicy[K, V]) Restore(entries []PolicyEntry[K]) {
+	p.freqOf = make(map[K]int, len(entries))
+	p.nodes = make(map[K]*list.Element, len(entries))
+	p.buckets = make(map[int]*list.List)
+	p.minFreq = 0
+
+	for _, e := range entries {
+		freq := int(e.Weight)
+		if freq < 1 {
+			freq = 1
+		}
+		l := p.buckets[freq]
+		if l == nil {
+			l = list.New()
+			p.buckets[freq] = l
+		}
+		p.freqOf[e.Key] = freq
+		p.nodes[e.Key] = l.PushBack(e.Key)
+		if p.minFreq == 0 || freq < p.minFreq {
+			p.minFreq = freq
+		}
+	}
+}
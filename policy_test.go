@@ -0,0 +1,53 @@
+package tcache
+
+import "testing"
+
+// TestLRUPolicyEvictionOrder verifies OnAccess promotes a key out of
+// eviction danger and Evict picks the least-recently-used key.
+func TestLRUPolicyEvictionOrder(t *testing.T) {
+	p := NewLRUPolicy[string, int]()
+	p.OnInsert("a")
+	p.OnInsert("b")
+	p.OnInsert("c")
+
+	p.OnAccess("a") // "a" is now the most recently used.
+
+	if key, ok := p.Evict(); !ok || key != "b" {
+		t.Fatalf("Evict() = %q, %v, want %q, true", key, ok, "b")
+	}
+}
+
+// TestFIFOPolicyEvictionOrder verifies FIFO evicts in insertion order
+// regardless of access.
+func TestFIFOPolicyEvictionOrder(t *testing.T) {
+	p := NewFIFOPolicy[string, int]()
+	p.OnInsert("a")
+	p.OnInsert("b")
+	p.OnInsert("c")
+
+	p.OnAccess("a") // FIFO ignores access; "a" should still go first.
+
+	if key, ok := p.Evict(); !ok || key != "a" {
+		t.Fatalf("Evict() = %q, %v, want %q, true", key, ok, "a")
+	}
+}
+
+// TestLFUPolicyEvictionOrder verifies LFU evicts the least-frequently
+// accessed key, not the least-recently inserted one.
+func TestLFUPolicyEvictionOrder(t *testing.T) {
+	p := NewLFUPolicy[string, int]()
+	p.OnInsert("a")
+	p.OnInsert("b")
+	p.OnInsert("c")
+
+	p.OnAccess("a")
+	p.OnAccess("a")
+	p.OnAccess("c")
+	// "b" was only ever touched once, by its insertion: it is least
+	// frequent and should be evicted first even though "a" was inserted
+	// before it.
+
+	if key, ok := p.Evict(); !ok || key != "b" {
+		t.Fatalf("Evict() = %q, %v, want %q, true", key, ok, "b")
+	}
+}
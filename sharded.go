@@ -0,0 +1,174 @@
+package tcache
+
+import (
+	"hash/maphash"
+	"runtime"
+	"time"
+)
+
+// Hasher computes the shard-routing hash for a key. ShardedCache hashes
+// string and []byte keys out of the box with hash/maphash; any other key
+// type needs a Hasher supplied via WithHasher.
+type Hasher[K comparable] func(key K) uint64
+
+// defaultHasher returns a Hasher that handles the two key types tcache can
+// hash without help from the caller.
+func defaultHasher[K comparable]() Hasher[K] {
+	seed := maphash.MakeSeed()
+	return func(key K) uint64 {
+		var h maphash.Hash
+		h.SetSeed(seed)
+		switch v := any(key).(type) {
+		case string:
+			h.WriteString(v)
+		case []byte:
+			h.Write(v)
+		default:
+			panic("tcache: key types other than string and []byte need a Hasher; supply one with WithHasher (ShardedCache) or TinyLFU.Hash")
+		}
+		return h.Sum64()
+	}
+}
+
+// ShardedCache spreads keys across N independent Cache[K, V] shards, each
+// with its own lock, so operations on different keys don't contend on a
+// single mutex. This is the approach bigcache, ristretto and similar caches
+// use to scale past what one RWMutex can sustain.
+type ShardedCache[K comparable, V any] struct {
+	shards []*Cache[K, V]
+	hash   Hasher[K]
+}
+
+type shardedConfig[K comparable, V any] struct {
+	shards    int
+	hash      Hasher[K]
+	cacheOpts []Option[K, V]
+}
+
+// ShardedOption configures a ShardedCache at construction time. See NewSharded.
+type ShardedOption[K comparable, V any] func(*shardedConfig[K, V])
+
+// WithShards sets the number of shards. It is rounded up to the next power
+// of two so shard selection can mask instead of mod. The default is
+// runtime.GOMAXPROCS(0) rounded up the same way.
+func WithShards[K comparable, V any](n int) ShardedOption[K, V] {
+	return func(cfg *shardedConfig[K, V]) { cfg.shards = n }
+}
+
+// WithHasher sets the Hasher used to route keys to shards. Required for key
+// types other than string and []byte.
+func WithHasher[K comparable, V any](h Hasher[K]) ShardedOption[K, V] {
+	return func(cfg *shardedConfig[K, V]) { cfg.hash = h }
+}
+
+// WithShardOptions forwards Options (such as WithPolicy) to every
+// underlying per-shard Cache.
+func WithShardOptions[K comparable, V any](opts ...Option[K, V]) ShardedOption[K, V] {
+	return func(cfg *shardedConfig[K, V]) { cfg.cacheOpts = append(cfg.cacheOpts, opts...) }
+}
+
+func nextPowerOfTwo(n int) int {
+	if n < 1 {
+		return 1
+	}
+	p := 1
+	for p < n {
+		p <<= 1
+	}
+	return p
+}
+
+// NewSharded creates a ShardedCache with the given total capacity split
+// evenly across its shards.
+func NewSharded[K comparable, V any](capacity int, opts ...ShardedOption[K, V]) *ShardedCache[K, V] {
+	cfg := &shardedConfig[K, V]{shards: nextPowerOfTwo(runtime.GOMAXPROCS(0))}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	cfg.shards = nextPowerOfTwo(cfg.shards)
+	if cfg.hash == nil {
+		cfg.hash = defaultHasher[K]()
+	}
+
+	perShard := capacity / cfg.shards
+	if perShard < 1 {
+		perShard = 1
+	}
+
+	sc := &ShardedCache[K, V]{shards: make([]*Cache[K, V], cfg.shards), hash: cfg.hash}
+	for i := range sc.shards {
+		sc.shards[i] = New[K, V](perShard, cfg.cacheOpts...)
+	}
+	return sc
+}
+
+func (sc *ShardedCache[K, V]) shardFor(key K) *Cache[K, V] {
+	idx := sc.hash(key) & uint64(len(sc.shards)-1)
+	return sc.shards[idx]
+}
+
+func (sc *ShardedCache[K, V]) Get(key K) (V, bool) {
+	return sc.shardFor(key).Get(key)
+}
+
+func (sc *ShardedCache[K, V]) Set(key K, value V) {
+	sc.shardFor(key).Set(key, value)
+}
+
+func (sc *ShardedCache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	sc.shardFor(key).SetWithTTL(key, value, ttl)
+}
+
+func (sc *ShardedCache[K, V]) Delete(key K) {
+	sc.shardFor(key).Delete(key)
+}
+
+func (sc *ShardedCache[K, V]) Has(key K) bool {
+	return sc.shardFor(key).Has(key)
+}
+
+// Len returns the total number of entries across all shards.
+func (sc *ShardedCache[K, V]) Len() int {
+	total := 0
+	for _, shard := range sc.shards {
+		total += shard.Len()
+	}
+	return total
+}
+
+// Range iterates every shard in turn, calling fn for each entry. There is
+// no lock held across shards, only the per-shard lock each Cache.Range
+// already takes, so Range never blocks the whole cache at once.
+func (sc *ShardedCache[K, V]) Range(fn func(K, V) bool) {
+	for _, shard := range sc.shards {
+		done := false
+		shard.Range(func(key K, value V) bool {
+			if !fn(key, value) {
+				done = true
+				return false
+			}
+			return true
+		})
+		if done {
+			return
+		}
+	}
+}
+
+// GetAllItems returns every entry across all shards merged into one map.
+func (sc *ShardedCache[K, V]) GetAllItems() map[K]V {
+	items := make(map[K]V)
+	for _, shard := range sc.shards {
+		for key, value := range shard.GetAllItems() {
+			items[key] = value
+		}
+	}
+	return items
+}
+
+// Close stops the background expiration goroutine on every shard.
+func (sc *ShardedCache[K, V]) Close() {
+	for _, shard := range sc.shards {
+		shard.Close()
+	}
+}
@@ -0,0 +1,41 @@
+package tcache
+
+import "testing"
+
+// TestShardedCacheRoutesConsistently verifies a key always lands on the
+// same shard, so a value set once can be read back through Get/Has.
+func TestShardedCacheRoutesConsistently(t *testing.T) {
+	sc := NewSharded[string, int](64, WithShards[string, int](4))
+	defer sc.Close()
+
+	for i := 0; i < 50; i++ {
+		key := keyFor(i)
+		sc.Set(key, i)
+	}
+
+	for i := 0; i < 50; i++ {
+		key := keyFor(i)
+		if !sc.Has(key) {
+			t.Fatalf("Has(%q) = false, want true", key)
+		}
+		if v, ok := sc.Get(key); !ok || v != i {
+			t.Fatalf("Get(%q) = %d, %v, want %d, true", key, v, ok, i)
+		}
+	}
+}
+
+// TestShardedCacheSplitsCapacityAcrossShards verifies the total capacity is
+// divided across shards rather than given to each shard in full, so the
+// cache as a whole doesn't silently hold far more than requested.
+func TestShardedCacheSplitsCapacityAcrossShards(t *testing.T) {
+	sc := NewSharded[string, int](8, WithShards[string, int](4))
+	defer sc.Close()
+
+	for i := 0; i < 1000; i++ {
+		sc.Set(keyFor(i), i)
+	}
+
+	if n := sc.Len(); n > 8 {
+		t.Fatalf("Len() = %d, want <= 8 (total capacity)", n)
+	}
+}
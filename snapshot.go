@@ -0,0 +1,129 @@
+package tcache
+
+import (
+	"compress/gzip"
+	"encoding/gob"
+	"io"
+	"os"
+	"time"
+)
+
+// snapshotEntry is the on-disk representation of one cache entry. TTL is
+// the remaining time-to-live at the moment of the snapshot, not the
+// original TTL, so a restored cache expires entries at the same wall-clock
+// time the original would have. Weight is the policy's own recency/
+// frequency weight for the key, as returned by Policy.Snapshot, so Restore
+// can reconstruct eviction order and frequency rather than resetting it.
+type snapshotEntry[K comparable, V any] struct {
+	Key    K
+	Value  V
+	TTL    time.Duration
+	Weight int64
+}
+
+// Save writes a snapshot of the cache to w: every entry's key, value,
+// remaining TTL and policy weight, gzip-compressed and gob-encoded so
+// arbitrary V works without per-type tags. It takes a read-snapshot of the
+// items under RLock into a slice and releases the lock before doing any
+// I/O, so Save does not stall concurrent readers for the duration of the
+// write.
+func (c *Cache[K, V]) Save(w io.Writer) error {
+	c.mu.RLock()
+	policyEntries := c.policy.Snapshot()
+	now := time.Now()
+	entries := make([]snapshotEntry[K, V], 0, len(policyEntries))
+	for _, pe := range policyEntries {
+		item, ok := c.items[pe.Key]
+		if !ok {
+			continue
+		}
+		var ttl time.Duration
+		if item.scheduled {
+			ttl = item.expiresAt.Sub(now)
+			if ttl <= 0 {
+				continue // expired between the wheel firing and us reading it
+			}
+		}
+		entries = append(entries, snapshotEntry[K, V]{Key: pe.Key, Value: item.value, TTL: ttl, Weight: pe.Weight})
+	}
+	c.mu.RUnlock()
+
+	gz := gzip.NewWriter(w)
+	if err := gob.NewEncoder(gz).Encode(entries); err != nil {
+		gz.Close()
+		return err
+	}
+	return gz.Close()
+}
+
+// Load replaces the cache's entire contents with a snapshot written by
+// Save: it re-arms TTLs from the remaining durations that were saved and
+// restores the policy's eviction order and per-key weight (e.g. LFU
+// frequency), so a restored cache behaves like one that had stayed warm
+// rather than one whose entries were just reinserted fresh.
+func (c *Cache[K, V]) Load(r io.Reader) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	var entries []snapshotEntry[K, V]
+	if err := gob.NewDecoder(gz).Decode(&entries); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.items {
+		c.removeLocked(key)
+	}
+
+	// Save writes entries in the policy's best-to-worst order, so trimming
+	// the tail here keeps the entries a live cache would have kept and
+	// drops the ones it would already have evicted to make room. Without
+	// this, a restored cache can hold more than c.capacity entries
+	// indefinitely, unlike a live one where every Set/SetWithTTL enforces
+	// it.
+	if c.capacity > 0 && len(entries) > c.capacity {
+		entries = entries[:c.capacity]
+	}
+
+	policyEntries := make([]PolicyEntry[K], 0, len(entries))
+	for _, e := range entries {
+		item := &Item[K, V]{key: e.Key, value: e.Value, ttl: e.TTL}
+		if c.costFn != nil {
+			item.cost = c.costFn(e.Key, e.Value)
+			c.totalCost += item.cost
+		}
+		c.items[e.Key] = item
+		c.armLocked(e.Key, item)
+		policyEntries = append(policyEntries, PolicyEntry[K]{Key: e.Key, Weight: e.Weight})
+	}
+	c.policy.Restore(policyEntries)
+
+	return nil
+}
+
+// SaveFile is a convenience wrapper around Save that writes the snapshot to
+// filename, creating or truncating it.
+func (c *Cache[K, V]) SaveFile(filename string) error {
+	f, err := os.Create(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Save(f)
+}
+
+// LoadFile is a convenience wrapper around Load that reads the snapshot
+// from filename.
+func (c *Cache[K, V]) LoadFile(filename string) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return c.Load(f)
+}
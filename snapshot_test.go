@@ -0,0 +1,112 @@
+package tcache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+// TestSnapshotRoundTripPreservesLFUFrequency verifies that restoring a
+// snapshot brings LFU keys back at the frequency they had, not reset to 1
+// as if freshly inserted — otherwise a restored LFU cache would have no
+// hot-key protection until it warmed back up from scratch.
+func TestSnapshotRoundTripPreservesLFUFrequency(t *testing.T) {
+	c := New[string, int](2, WithPolicy[string, int](NewLFUPolicy[string, int]()))
+	defer c.Close()
+
+	c.Set("hot", 1)
+	c.Set("cold", 2)
+	// Access "hot" repeatedly so it is far more frequent than "cold".
+	for i := 0; i < 5; i++ {
+		c.Get("hot")
+	}
+
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	restored := New[string, int](2, WithPolicy[string, int](NewLFUPolicy[string, int]()))
+	defer restored.Close()
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if v, ok := restored.Get("hot"); !ok || v != 1 {
+		t.Fatalf(`Get("hot") = %d, %v, want 1, true`, v, ok)
+	}
+	if v, ok := restored.Get("cold"); !ok || v != 2 {
+		t.Fatalf(`Get("cold") = %d, %v, want 2, true`, v, ok)
+	}
+
+	// Insert a third key into the still-full, restored cache: if frequency
+	// was preserved, "cold" (the least frequent) is evicted, not "hot".
+	restored.Set("new", 3)
+
+	if !restored.Has("hot") {
+		t.Fatalf(`expected "hot" to survive eviction after restore`)
+	}
+	if restored.Has("cold") {
+		t.Fatalf(`expected "cold" to have been evicted after restore`)
+	}
+}
+
+// TestSnapshotRoundTripPreservesTTL verifies that a restored entry keeps
+// expiring around the same wall-clock time as the original.
+func TestSnapshotRoundTripPreservesTTL(t *testing.T) {
+	const tick = 30 * time.Millisecond
+
+	c := NewWithWheel[string, string](10, tick, 300)
+	defer c.Close()
+
+	c.SetWithTTL("k", "v", 3*tick)
+
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	restored := NewWithWheel[string, string](10, tick, 300)
+	defer restored.Close()
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if v, ok := restored.Get("k"); !ok || v != "v" {
+		t.Fatalf(`Get("k") = %q, %v, want "v", true`, v, ok)
+	}
+
+	time.Sleep(8 * tick)
+
+	if restored.Has("k") {
+		t.Fatalf(`expected "k" to have expired after restore`)
+	}
+}
+
+// TestLoadEnforcesCapacity verifies that restoring a snapshot taken from a
+// larger cache does not leave the restored cache holding more than its own
+// capacity — a live cache can never exceed capacity, so a restored one
+// shouldn't either.
+func TestLoadEnforcesCapacity(t *testing.T) {
+	c := New[string, int](50, WithPolicy[string, int](NewLFUPolicy[string, int]()))
+	defer c.Close()
+
+	for i := 0; i < 50; i++ {
+		c.Set(keyFor(i), i)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Save(&buf); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	restored := New[string, int](5, WithPolicy[string, int](NewLFUPolicy[string, int]()))
+	defer restored.Close()
+	if err := restored.Load(&buf); err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	if n := restored.Len(); n > 5 {
+		t.Fatalf("Len() = %d, want <= 5", n)
+	}
+}
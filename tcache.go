@@ -1,12 +1,10 @@
 package tcache
 
 import (
-	"container/list"
 	"encoding/json"
 	"os"
 	"sync"
 	"time"
-	"unsafe"
 )
 
 func (c *Cache[K, V]) OnEvict(fn func(K, V)) {
@@ -56,51 +54,116 @@ func (c *Cache[K, V]) SetCapacity(capacity int) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	c.capacity = capacity
-	for c.list.Len() > c.capacity {
-		backItem := c.list.Back()
-		if backItem != nil {
-			delete(c.items, backItem.Value.(*Item[K, V]).key)
-			c.list.Remove(c.list.Back())
-			if c.onEvict != nil {
-				c.onEvict(backItem.Value.(*Item[K, V]).key, backItem.Value.(*Item[K, V]).value)
-			}
+	for len(c.items) > c.capacity {
+		if !c.evictOneLocked() {
+			break
 		}
 	}
 }
 
-func (c *Cache[K, V]) Refresh(key K, ttl time.Duration) {
+// evictOneLocked asks the policy for its eviction victim and removes it.
+// Callers must hold c.mu. It reports whether an item was evicted.
+func (c *Cache[K, V]) evictOneLocked() bool {
+	victim, ok := c.policy.Evict()
+	if !ok {
+		return false
+	}
+	return c.evictVictimLocked(victim)
+}
+
+// evictVictimLocked removes a specific, already-chosen victim. Callers must
+// hold c.mu. It reports whether an item was evicted.
+func (c *Cache[K, V]) evictVictimLocked(victim K) bool {
+	item, exists := c.items[victim]
+	if !exists {
+		return false
+	}
+	c.removeLocked(victim)
+	if c.onEvict != nil {
+		c.onEvict(victim, item.value)
+	}
+	if item.onWatch != nil {
+		item.onWatch(victim, EVICT, item.value, item.value)
+	}
+	return true
+}
+
+// removeLocked drops key from items and the policy, cancelling its wheel
+// entry if any. Callers must hold c.mu.
+func (c *Cache[K, V]) removeLocked(key K) {
+	item, exists := c.items[key]
+	if !exists {
+		return
+	}
+	if item.scheduled {
+		c.wheel.cancel(key, item.wheelSlot)
+	}
+	delete(c.items, key)
+	c.policy.OnRemove(key)
+	c.totalCost -= item.cost
+}
+
+// armLocked (re)schedules key's expiration on the wheel based on item.ttl.
+// Callers must hold c.mu.
+func (c *Cache[K, V]) armLocked(key K, item *Item[K, V]) {
+	if item.scheduled {
+		c.wheel.cancel(key, item.wheelSlot)
+		item.scheduled = false
+	}
+	if item.ttl <= 0 {
+		item.expiresAt = time.Time{}
+		return
+	}
+	item.wheelSlot = c.wheel.schedule(key, item.ttl)
+	item.scheduled = true
+	item.expiresAt = time.Now().Add(item.ttl)
+}
+
+// handleExpire is the wheel's fire callback: it removes key if it is still
+// present and fires onExpire. A key can be gone already if it was deleted
+// or updated with a new TTL between being scheduled and firing.
+func (c *Cache[K, V]) handleExpire(key K) {
 	c.mu.Lock()
-	defer c.mu.Unlock()
-	if item, exists := c.items[key]; exists {
-		// stop timer if it exists
-		if timer := item.Value.(*Item[K, V]).timer; timer != nil {
-			timer.Stop()
-		}
+	item, exists := c.items[key]
+	if !exists {
+		c.mu.Unlock()
+		return
+	}
+	value := item.value
+	c.removeLocked(key)
+	c.mu.Unlock()
 
-		if ttl > 0 {
-			item.Value.(*Item[K, V]).timer = time.AfterFunc(ttl, func() {
-				if c.onDelete != nil {
-					c.onDelete(key, item.Value.(*Item[K, V]).value)
-				}
-				c.Delete(key)
-				c.list.Remove(item)
-			})
-		}
+	if c.onExpire != nil {
+		c.onExpire(key, value)
+	}
+}
 
-		item.Value.(*Item[K, V]).ttl = ttl
+func (c *Cache[K, V]) Refresh(key K, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	item, exists := c.items[key]
+	if !exists {
+		return
 	}
+	item.ttl = ttl
+	c.armLocked(key, item)
 }
+
 func (c *Cache[K, V]) Update(key K, value V) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if item, exists := c.items[key]; exists {
-		item.Value.(*Item[K, V]).value = value
-		if c.onUpdate != nil {
-			c.onUpdate(key, value, item.Value.(*Item[K, V]).value)
-		}
+	item, exists := c.items[key]
+	if !exists {
+		return
+	}
+	oldValue := item.value
+	item.value = value
+	if c.onUpdate != nil {
+		c.onUpdate(key, oldValue, value)
 	}
 }
+
 func (c *Cache[K, V]) UpdateWithTTL(key K, value V, ttl time.Duration) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -109,47 +172,30 @@ func (c *Cache[K, V]) UpdateWithTTL(key K, value V, ttl time.Duration) {
 	if !exists {
 		return
 	}
-	if item.Value.(*Item[K, V]).timer != nil {
-		item.Value.(*Item[K, V]).timer.Stop()
-	}
-
-	var timer *time.Timer
-	if ttl > 0 {
-		timer = time.AfterFunc(ttl, func() {
-			if item, exists := c.items[key]; exists {
-				c.list.Remove(item)
-				delete(c.items, key)
-				if c.onExpire != nil {
-					c.onExpire(key, value)
-				}
-			}
-		})
-	}
 
-	item.Value.(*Item[K, V]).value = value
-	item.Value.(*Item[K, V]).timer = timer
+	oldValue := item.value
+	item.value = value
+	item.ttl = ttl
+	c.armLocked(key, item)
 
 	if c.onUpdate != nil {
-		c.onUpdate(key, value, item.Value.(*Item[K, V]).value)
+		c.onUpdate(key, oldValue, value)
 	}
 }
+
 func (c *Cache[K, V]) Delete(key K) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
-	if item, exists := c.items[key]; exists {
-		if item.Value.(*Item[K, V]).timer != nil {
-			item.Value.(*Item[K, V]).timer.Stop()
-		}
-		delete(c.items, key)
-		c.list.Remove(item)
-		if c.onDelete != nil {
-			c.onDelete(key, item.Value.(*Item[K, V]).value)
-		}
-
-		if item.Value.(*Item[K, V]).onWatch != nil {
-			item.Value.(*Item[K, V]).onWatch(key, DELETE, item.Value.(*Item[K, V]).value, item.Value.(*Item[K, V]).value)
-		}
-
+	item, exists := c.items[key]
+	if !exists {
+		return
+	}
+	c.removeLocked(key)
+	if c.onDelete != nil {
+		c.onDelete(key, item.value)
+	}
+	if item.onWatch != nil {
+		item.onWatch(key, DELETE, item.value, item.value)
 	}
 }
 
@@ -157,13 +203,10 @@ func (c *Cache[K, V]) DeleteAll() {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	for key, item := range c.items {
-		if item.Value.(*Item[K, V]).timer != nil {
-			item.Value.(*Item[K, V]).timer.Stop()
-			if c.onDelete != nil {
-				c.onDelete(key, item.Value.(*Item[K, V]).value)
-			}
-			c.list.Remove(item)
-			delete(c.items, key)
+		value := item.value
+		c.removeLocked(key)
+		if c.onDelete != nil {
+			c.onDelete(key, value)
 		}
 	}
 }
@@ -174,7 +217,7 @@ func (c *Cache[K, V]) GetAllItems() map[K]V {
 
 	items := make(map[K]V, len(c.items))
 	for key, item := range c.items {
-		items[key] = item.Value.(*Item[K, V]).value
+		items[key] = item.value
 	}
 	return items
 }
@@ -185,7 +228,7 @@ func (c *Cache[K, V]) GetAllValues() []V {
 
 	values := make([]V, 0, len(c.items))
 	for _, item := range c.items {
-		values = append(values, item.Value.(*Item[K, V]).value)
+		values = append(values, item.value)
 	}
 	return values
 }
@@ -203,62 +246,112 @@ func (c *Cache[K, V]) GetAllKeys() []K {
 }
 
 func (c *Cache[K, V]) Get(key K) (V, bool) {
-	c.mu.RLock()
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	item, exists := c.items[key]
 	if !exists {
-		c.mu.RUnlock()
 		var zero V
 		return zero, false
 	}
 
-	c.mu.RUnlock()
-	c.Refresh(key, item.Value.(*Item[K, V]).ttl)
-	c.list.MoveToFront(item)
-	return item.Value.(*Item[K, V]).value, true
+	c.armLocked(key, item)
+	c.policy.OnAccess(key)
+	if c.admission != nil {
+		c.admission.record(key)
+	}
+	return item.value, true
 }
 
 func (c *Cache[K, V]) Set(key K, value V) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	c.setLocked(key, value, 0)
+}
+
+func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setLocked(key, value, ttl)
+}
+
+func (c *Cache[K, V]) setLocked(key K, value V, ttl time.Duration) {
 	item, exists := c.items[key]
+
+	var cost int64
+	if c.costFn != nil {
+		cost = c.costFn(key, value)
+	}
+	oldCost := int64(0)
 	if exists {
-		if item.Value.(*Item[K, V]).timer != nil {
-			item.Value.(*Item[K, V]).timer.Stop()
-		}
-		c.list.MoveToFront(item)
+		oldCost = item.cost
 	}
 
-	if c.list.Len() >= c.capacity {
-		backItem := c.list.Back()
-		if backItem != nil {
-			delete(c.items, backItem.Value.(*Item[K, V]).key)
-			c.list.Remove(backItem)
-			if c.onEvict != nil {
-				c.onEvict(backItem.Value.(*Item[K, V]).key, backItem.Value.(*Item[K, V]).value)
+	if !exists {
+		if c.admission != nil {
+			c.admission.record(key)
+		}
+		for len(c.items) >= c.capacity {
+			victim, ok := c.policy.Evict()
+			if !ok {
+				break
+			}
+			if c.admission != nil && !c.admission.admit(key, victim) {
+				// The incoming key loses to the current occupant's
+				// estimated frequency: reject it outright instead of
+				// evicting to make room.
+				return
+			}
+			if !c.evictVictimLocked(victim) {
+				break
+			}
+		}
+	}
+	if c.maxCost > 0 {
+		for c.totalCost-oldCost+cost > c.maxCost {
+			victim, ok := c.policy.Evict()
+			if !ok || victim == key {
+				// Either nothing left to evict, or the policy's only
+				// eviction candidate is the very key being updated right
+				// now. Evicting it here would delete it from c.items while
+				// policy.OnInsert below re-adds it to the eviction order
+				// below, leaving a phantom entry that panics the next time
+				// it's picked. Stop making room instead of evicting self.
+				break
 			}
-			if exists {
-				if item.Value.(*Item[K, V]).onWatch != nil {
-					item.Value.(*Item[K, V]).onWatch(key, EVICT, value, value)
-				}
+			if !c.evictVictimLocked(victim) {
+				break
 			}
 		}
 	}
 
-	var newItem *Item[K, V]
+	var oldValue V
+	var onWatch func(K, Operation, V, V)
+	op := INSERT
 	if exists {
-		if item.Value.(*Item[K, V]).onWatch != nil {
-			newItem = &Item[K, V]{value: value, key: key, onWatch: item.Value.(*Item[K, V]).onWatch}
-		}
+		oldValue = item.value
+		onWatch = item.onWatch
+		op = UPDATE
 	} else {
-		newItem = &Item[K, V]{value: value, key: key}
+		item = &Item[K, V]{key: key}
+		c.items[key] = item
 	}
 
-	c.items[key] = c.list.PushFront(newItem)
-	if c.items[key].Value.(*Item[K, V]).onWatch != nil {
-		item.Value.(*Item[K, V]).onWatch(key, UPDATE, item.Value.(*Item[K, V]).value, newItem.value)
+	c.totalCost += cost - oldCost
+	item.value = value
+	item.ttl = ttl
+	item.cost = cost
+	c.armLocked(key, item)
+	c.policy.OnInsert(key)
+
+	if onWatch != nil {
+		onWatch(key, op, oldValue, value)
 	}
 
-	if c.onInsert != nil {
+	if exists {
+		if c.onUpdate != nil {
+			c.onUpdate(key, oldValue, value)
+		}
+	} else if c.onInsert != nil {
 		c.onInsert(key, value)
 	}
 }
@@ -268,86 +361,22 @@ func (c *Cache[K, V]) Range(fn func(K, V) bool) {
 	defer c.mu.Unlock()
 
 	for key, item := range c.items {
-		if !fn(key, item.Value.(*Item[K, V]).value) {
+		if !fn(key, item.value) {
 			break
 		}
 	}
 }
 
-func (c *Cache[K, V]) SetWithTTL(key K, value V, ttl time.Duration) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-
-	item, exists := c.items[key]
-	if exists {
-		item.Value.(*Item[K, V]).timer.Stop()
-		c.list.MoveToFront(item)
-	} else {
-
-	}
-
-	var timer *time.Timer
-	if ttl > 0 {
-		timer = time.AfterFunc(ttl, func() {
-			if item, exists := c.items[key]; exists {
-				c.list.Remove(item)
-				delete(c.items, key)
-				if c.onExpire != nil {
-					c.onExpire(key, value)
-				}
-			}
-		})
-	}
-
-	if c.list.Len() >= c.capacity {
-		backItem := c.list.Back()
-		if backItem != nil {
-			if backItem.Value.(*Item[K, V]).timer != nil {
-				backItem.Value.(*Item[K, V]).timer.Stop()
-			}
-			delete(c.items, backItem.Value.(*Item[K, V]).key)
-			c.list.Remove(backItem)
-			if c.onEvict != nil {
-				c.onEvict(backItem.Value.(*Item[K, V]).key, backItem.Value.(*Item[K, V]).value)
-			}
-			if exists {
-				if item.Value.(*Item[K, V]).onWatch != nil {
-					item.Value.(*Item[K, V]).onWatch(key, EVICT, value, value)
-				}
-			}
-
-		}
-
-	}
-	var newItem *Item[K, V]
-	if exists {
-		if item.Value.(*Item[K, V]).onWatch != nil {
-			newItem = &Item[K, V]{value: value, key: key, timer: timer, onWatch: item.Value.(*Item[K, V]).onWatch}
-		}
-	} else {
-		newItem = &Item[K, V]{value: value, key: key, timer: timer, ttl: ttl}
-	}
-
-	c.items[key] = c.list.PushFront(newItem)
-	if c.items[key].Value.(*Item[K, V]).onWatch != nil {
-		item.Value.(*Item[K, V]).onWatch(key, UPDATE, item.Value.(*Item[K, V]).value, newItem.value)
-	}
-
-	if c.onInsert != nil {
-		c.onInsert(key, value)
-	}
-}
-
+// SizeInBytes returns the accumulated cost of every entry, as computed by
+// the Cost function passed to WithCost/WithWeigher. It is 0 if no cost
+// function was configured.
 func (c *Cache[K, V]) SizeInBytes() uint64 {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
-	var size uint64
-
-	for _, items := range c.items {
-		size += uint64(unsafe.Sizeof(items.Value.(*Item[K, V]).value))
+	if c.totalCost < 0 {
+		return 0
 	}
-
-	return size
+	return uint64(c.totalCost)
 }
 
 func (c *Cache[K, V]) ExportToFile(filename, exp string) error {
@@ -355,9 +384,8 @@ func (c *Cache[K, V]) ExportToFile(filename, exp string) error {
 	defer c.mu.Unlock()
 	itemsMap := make(map[K]V, len(c.items))
 
-	for _, item := range c.items {
-		itemTyped := item.Value.(*Item[K, V])
-		itemsMap[itemTyped.key] = itemTyped.value
+	for key, item := range c.items {
+		itemsMap[key] = item.value
 	}
 
 	data, err := json.Marshal(itemsMap)
@@ -368,44 +396,79 @@ func (c *Cache[K, V]) ExportToFile(filename, exp string) error {
 	return os.WriteFile(filename+exp, data, 0644)
 }
 
-func New[K comparable, V any](capacity int) *Cache[K, V] {
-	return &Cache[K, V]{
-		items:    make(map[K]*list.Element),
-		list:     list.New(),
+// New creates a Cache with the given capacity. By default it evicts with
+// NewLRUPolicy; pass WithPolicy to choose a different eviction policy (for
+// example NewLFUPolicy or NewFIFOPolicy). TTL expiration uses a hashed
+// timing wheel ticking every second over 300 slots; use NewWithWheel to
+// tune that.
+func New[K comparable, V any](capacity int, opts ...Option[K, V]) *Cache[K, V] {
+	return NewWithWheel[K, V](capacity, defaultWheelTick, defaultWheelSlots, opts...)
+}
+
+// NewWithWheel creates a Cache like New but with a hashed timing wheel of
+// slots slots advancing every tick, instead of the defaults. A single
+// background goroutine scans one slot per tick to expire due entries, which
+// avoids running a time.Timer per TTL entry under high churn. Call Close
+// when the Cache is no longer needed to stop that goroutine.
+func NewWithWheel[K comparable, V any](capacity int, tick time.Duration, slots int, opts ...Option[K, V]) *Cache[K, V] {
+	c := &Cache[K, V]{
+		items:    make(map[K]*Item[K, V]),
+		policy:   NewLRUPolicy[K, V](),
 		capacity: capacity,
 	}
+	c.wheel = newWheel[K](tick, slots, c.handleExpire)
+	c.wheel.start()
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Close stops the background goroutine that advances the expiration wheel.
+// A Cache that will no longer be used should be closed to avoid leaking it.
+func (c *Cache[K, V]) Close() {
+	c.wheel.stop()
 }
 
 func (c *Cache[K, V]) OnWatch(key K, fn func(key K, op Operation, oldValue V, newValue V)) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	if item, exists := c.items[key]; exists {
-		item.Value.(*Item[K, V]).onWatch = fn
-
-	} else {
-		newItem := &Item[K, V]{key: key, onWatch: fn}
-		c.items[key] = c.list.PushFront(newItem)
+	item, exists := c.items[key]
+	if !exists {
+		item = &Item[K, V]{key: key}
+		c.items[key] = item
+		c.policy.OnInsert(key)
 	}
+	item.onWatch = fn
 }
 
 type Cache[K comparable, V any] struct {
-	items    map[K]*list.Element
-	list     *list.List
-	mu       sync.RWMutex
-	capacity int //max size cache
-	onInsert func(K, V)
-	onUpdate func(K, V, V)
-	onDelete func(K, V)
-	onExpire func(K, V)
-	onEvict  func(K, V)
+	items     map[K]*Item[K, V]
+	policy    Policy[K, V]
+	wheel     *wheel[K]
+	inflight  map[K]*call[V]
+	mu        sync.RWMutex
+	capacity  int //max size cache
+	costFn    func(K, V) int64
+	maxCost   int64
+	totalCost int64
+	admission *admissionFilter[K]
+	onInsert  func(K, V)
+	onUpdate  func(K, V, V)
+	onDelete  func(K, V)
+	onExpire  func(K, V)
+	onEvict   func(K, V)
 }
 
 type Item[K comparable, V any] struct {
 	key         K
 	value       V
-	timer       *time.Timer
 	ttl         time.Duration
+	expiresAt   time.Time // zero if ttl <= 0
+	scheduled   bool      // true if this key currently has a pending wheel entry
+	wheelSlot   int
+	cost        int64
 	onWatch, fn func(K, Operation, V, V)
 }
 
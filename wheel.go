@@ -0,0 +1,115 @@
+package tcache
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultWheelTick  = time.Second
+	defaultWheelSlots = 300
+)
+
+// wheel is a hashed timing wheel: a ring of slots advancing one position
+// every tick, each holding the keys due to expire during that window. A
+// single goroutine sweeps the current slot on every tick instead of every
+// TTL entry running its own time.Timer, which is what tcache used to do.
+//
+// Keys whose TTL spans more than one full rotation are scheduled into the
+// slot they'd land in on the correct lap and carry the number of remaining
+// laps; sweep decrements that counter instead of firing until it reaches
+// zero.
+type wheel[K comparable] struct {
+	tick   time.Duration
+	slots  []map[K]int
+	mu     sync.Mutex
+	cur    int
+	stopCh chan struct{}
+	onFire func(K)
+}
+
+func newWheel[K comparable](tick time.Duration, slotCount int, onFire func(K)) *wheel[K] {
+	if tick <= 0 {
+		tick = defaultWheelTick
+	}
+	if slotCount <= 0 {
+		slotCount = defaultWheelSlots
+	}
+	w := &wheel[K]{
+		tick:   tick,
+		slots:  make([]map[K]int, slotCount),
+		stopCh: make(chan struct{}),
+		onFire: onFire,
+	}
+	for i := range w.slots {
+		w.slots[i] = make(map[K]int)
+	}
+	return w
+}
+
+// schedule enqueues key to fire after ttl and returns the slot it landed in,
+// so the caller can cancel it later via cancel.
+func (w *wheel[K]) schedule(key K, ttl time.Duration) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	n := len(w.slots)
+	ticks := int(ttl / w.tick)
+	if ticks < 1 {
+		ticks = 1
+	}
+	slot := (w.cur + ticks) % n
+	w.slots[slot][key] = (ticks - 1) / n
+	return slot
+}
+
+// cancel removes key from the slot it was scheduled into, if still present.
+func (w *wheel[K]) cancel(key K, slot int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.slots[slot], key)
+}
+
+// start launches the background goroutine that advances the wheel. It must
+// only be called once per wheel.
+func (w *wheel[K]) start() {
+	go func() {
+		ticker := time.NewTicker(w.tick)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stopCh:
+				return
+			case <-ticker.C:
+				w.sweep()
+			}
+		}
+	}()
+}
+
+// sweep advances to the next slot and fires every key that has completed
+// its remaining laps; keys with laps left have their counter decremented
+// and stay put for the next full rotation.
+func (w *wheel[K]) sweep() {
+	w.mu.Lock()
+	w.cur = (w.cur + 1) % len(w.slots)
+	due := make([]K, 0)
+	slot := w.slots[w.cur]
+	for key, laps := range slot {
+		if laps > 0 {
+			slot[key] = laps - 1
+			continue
+		}
+		due = append(due, key)
+		delete(slot, key)
+	}
+	w.mu.Unlock()
+
+	for _, key := range due {
+		w.onFire(key)
+	}
+}
+
+func (w *wheel[K]) stop() {
+	close(w.stopCh)
+}
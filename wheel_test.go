@@ -0,0 +1,47 @@
+package tcache
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestWheelMultiLapExpiration verifies a TTL spanning more than one full
+// rotation of the wheel fires only after all of its laps have elapsed, not
+// on the first time its slot is swept.
+func TestWheelMultiLapExpiration(t *testing.T) {
+	const (
+		tick  = 10 * time.Millisecond
+		slots = 3
+	)
+
+	var mu sync.Mutex
+	fired := false
+	w := newWheel[string](tick, slots, func(key string) {
+		mu.Lock()
+		fired = true
+		mu.Unlock()
+	})
+	w.start()
+	defer w.stop()
+
+	// 7 ticks needs more than one full rotation of a 3-slot wheel (laps=2),
+	// so this only passes if sweep's lap counter is actually honored.
+	w.schedule("k", 7*tick)
+
+	time.Sleep(5 * tick)
+	mu.Lock()
+	firedEarly := fired
+	mu.Unlock()
+	if firedEarly {
+		t.Fatalf("expected key not to have fired yet after 5 of 7 ticks")
+	}
+
+	time.Sleep(5 * tick)
+	mu.Lock()
+	firedLate := fired
+	mu.Unlock()
+	if !firedLate {
+		t.Fatalf("expected key to have fired after its laps elapsed")
+	}
+}